@@ -0,0 +1,77 @@
+package ecl
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceIdentityUserGroupsV3() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceIdentityUserGroupsV3Read,
+
+		Schema: map[string]*schema.Schema{
+			"user_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"groups": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceIdentityUserGroupsV3Read(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	identityClient, err := config.identityV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	userID := d.Get("user_id").(string)
+
+	userGroups, err := resourceIdentityUserGroupMembershipV3ListGroups(identityClient, userID)
+	if err != nil {
+		return fmt.Errorf("Error listing groups for ECL user %s: %s", userID, err)
+	}
+
+	log.Printf("[DEBUG] Retrieved ECL groups for user %s: %#v", userID, userGroups)
+
+	groupList := make([]map[string]interface{}, len(userGroups))
+	for i, group := range userGroups {
+		groupList[i] = map[string]interface{}{
+			"id":   group.ID,
+			"name": group.Name,
+		}
+	}
+
+	d.SetId(userID)
+	d.Set("user_id", userID)
+	d.Set("groups", groupList)
+	d.Set("region", GetRegion(d, config))
+
+	return nil
+}
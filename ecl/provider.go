@@ -0,0 +1,62 @@
+package ecl
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Provider returns the ECL terraform.ResourceProvider, wiring every
+// resource and data source implemented in this package.
+func Provider() terraform.ResourceProvider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"auth_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ECL_AUTH_URL", nil),
+			},
+
+			"user_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ECL_USERNAME", ""),
+			},
+
+			"password": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ECL_PASSWORD", ""),
+			},
+
+			"domain_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ECL_DOMAIN_NAME", ""),
+			},
+
+			"tenant_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ECL_TENANT_ID", ""),
+			},
+
+			"region": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ECL_REGION", ""),
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"ecl_identity_user_v3":                  resourceIdentityUserV3(),
+			"ecl_identity_user_group_membership_v3": resourceIdentityUserGroupMembershipV3(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"ecl_identity_user_groups_v3": dataSourceIdentityUserGroupsV3(),
+		},
+
+		ConfigureFunc: configureProvider,
+	}
+}
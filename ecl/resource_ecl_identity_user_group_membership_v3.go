@@ -0,0 +1,298 @@
+package ecl
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/nttcom/eclcloud"
+	"github.com/nttcom/eclcloud/ecl/identity/v3/groups"
+	"github.com/nttcom/eclcloud/ecl/identity/v3/users"
+)
+
+func resourceIdentityUserGroupMembershipV3() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIdentityUserGroupMembershipV3Create,
+		Read:   resourceIdentityUserGroupMembershipV3Read,
+		Update: resourceIdentityUserGroupMembershipV3Update,
+		Delete: resourceIdentityUserGroupMembershipV3Delete,
+		Importer: &schema.ResourceImporter{
+			State: resourceIdentityUserGroupMembershipV3Import,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			// group_id is used in additive mode, to manage one specific
+			// pairing without disturbing groups other resources or teams
+			// may have added for the same user.
+			"group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			// group_ids is used in exclusive mode. This resource becomes
+			// authoritative for the user's full group set and reconciles
+			// drift by adding/removing groups to match it exactly.
+			"group_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "additive",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"additive", "exclusive"}, false),
+			},
+
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceIdentityUserGroupMembershipV3Create(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	identityClient, err := config.identityV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	userID := d.Get("user_id").(string)
+	mode := d.Get("mode").(string)
+
+	switch mode {
+	case "exclusive":
+		groupIDs := resourceIdentityUserGroupMembershipV3ExpandGroupIDs(d.Get("group_ids").(*schema.Set))
+		if len(groupIDs) == 0 {
+			return fmt.Errorf("group_ids must contain at least one group ID in exclusive mode")
+		}
+
+		if err := resourceIdentityUserGroupMembershipV3SyncExclusive(identityClient, userID, groupIDs); err != nil {
+			return err
+		}
+
+		d.SetId(userID)
+	case "additive":
+		groupID := d.Get("group_id").(string)
+		if groupID == "" {
+			return fmt.Errorf("group_id is required in additive mode")
+		}
+
+		if err := groups.AddUser(identityClient, groupID, userID).ExtractErr(); err != nil {
+			return fmt.Errorf("Error adding ECL user %s to group %s: %s", userID, groupID, err)
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s", userID, groupID))
+	}
+
+	return resourceIdentityUserGroupMembershipV3Read(d, meta)
+}
+
+func resourceIdentityUserGroupMembershipV3Read(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	identityClient, err := config.identityV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	userID := d.Get("user_id").(string)
+
+	userGroups, err := resourceIdentityUserGroupMembershipV3ListGroups(identityClient, userID)
+	if err != nil {
+		return fmt.Errorf("Error listing groups for ECL user %s: %s", userID, err)
+	}
+
+	switch d.Get("mode").(string) {
+	case "exclusive":
+		groupIDs := make([]interface{}, len(userGroups))
+		for i, group := range userGroups {
+			groupIDs[i] = group.ID
+		}
+		d.Set("group_ids", groupIDs)
+		d.SetId(userID)
+	default:
+		groupID := d.Get("group_id").(string)
+
+		var found bool
+		for _, group := range userGroups {
+			if group.ID == groupID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			log.Printf("[DEBUG] ECL user %s is no longer a member of group %s", userID, groupID)
+			d.SetId("")
+			return nil
+		}
+
+		d.Set("group_id", groupID)
+		d.SetId(fmt.Sprintf("%s/%s", userID, groupID))
+	}
+
+	d.Set("user_id", userID)
+	d.Set("region", GetRegion(d, config))
+
+	return nil
+}
+
+func resourceIdentityUserGroupMembershipV3Update(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("mode").(string) != "exclusive" {
+		return resourceIdentityUserGroupMembershipV3Read(d, meta)
+	}
+
+	config := meta.(*Config)
+	identityClient, err := config.identityV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	if d.HasChange("group_ids") {
+		userID := d.Get("user_id").(string)
+		groupIDs := resourceIdentityUserGroupMembershipV3ExpandGroupIDs(d.Get("group_ids").(*schema.Set))
+		if len(groupIDs) == 0 {
+			return fmt.Errorf("group_ids must contain at least one group ID in exclusive mode")
+		}
+
+		if err := resourceIdentityUserGroupMembershipV3SyncExclusive(identityClient, userID, groupIDs); err != nil {
+			return err
+		}
+	}
+
+	return resourceIdentityUserGroupMembershipV3Read(d, meta)
+}
+
+func resourceIdentityUserGroupMembershipV3Delete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	identityClient, err := config.identityV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	userID := d.Get("user_id").(string)
+
+	if d.Get("mode").(string) == "exclusive" {
+		for _, groupID := range resourceIdentityUserGroupMembershipV3ExpandGroupIDs(d.Get("group_ids").(*schema.Set)) {
+			if err := groups.RemoveUser(identityClient, groupID, userID).ExtractErr(); err != nil {
+				return fmt.Errorf("Error removing ECL user %s from group %s: %s", userID, groupID, err)
+			}
+		}
+
+		return nil
+	}
+
+	groupID := d.Get("group_id").(string)
+
+	err = groups.RemoveUser(identityClient, groupID, userID).ExtractErr()
+	if err != nil {
+		return fmt.Errorf("Error removing ECL user %s from group %s: %s", userID, groupID, err)
+	}
+
+	return nil
+}
+
+// resourceIdentityUserGroupMembershipV3Import parses an import ID of either
+// "<user_id>/<group_id>" (additive) or "<user_id>" (exclusive) and populates
+// user_id, group_id and mode accordingly before the next Read fills in the
+// rest of the state.
+func resourceIdentityUserGroupMembershipV3Import(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+
+	if strings.Contains(id, "/") {
+		parts := strings.SplitN(id, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("Invalid ECL user group membership import ID %q, must be <user_id>/<group_id> or <user_id>", id)
+		}
+
+		d.Set("user_id", parts[0])
+		d.Set("group_id", parts[1])
+		d.Set("mode", "additive")
+	} else {
+		d.Set("user_id", id)
+		d.Set("mode", "exclusive")
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceIdentityUserGroupMembershipV3ListGroups returns the groups the
+// given user currently belongs to.
+func resourceIdentityUserGroupMembershipV3ListGroups(identityClient *eclcloud.ServiceClient, userID string) ([]groups.Group, error) {
+	allPages, err := users.ListGroups(identityClient, userID).AllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	return groups.ExtractGroups(allPages)
+}
+
+// resourceIdentityUserGroupMembershipV3SyncExclusive adds userID to every
+// group in groupIDs and removes it from any other group it currently
+// belongs to, making the given set authoritative.
+func resourceIdentityUserGroupMembershipV3SyncExclusive(identityClient *eclcloud.ServiceClient, userID string, groupIDs []string) error {
+	userGroups, err := resourceIdentityUserGroupMembershipV3ListGroups(identityClient, userID)
+	if err != nil {
+		return fmt.Errorf("Error listing groups for ECL user %s: %s", userID, err)
+	}
+
+	current := make(map[string]bool, len(userGroups))
+	for _, group := range userGroups {
+		current[group.ID] = true
+	}
+
+	desired := make(map[string]bool, len(groupIDs))
+	for _, groupID := range groupIDs {
+		desired[groupID] = true
+	}
+
+	for _, groupID := range groupIDs {
+		if current[groupID] {
+			continue
+		}
+
+		if err := groups.AddUser(identityClient, groupID, userID).ExtractErr(); err != nil {
+			return fmt.Errorf("Error adding ECL user %s to group %s: %s", userID, groupID, err)
+		}
+	}
+
+	for _, group := range userGroups {
+		if desired[group.ID] {
+			continue
+		}
+
+		log.Printf("[DEBUG] Removing ECL user %s from extra group %s (exclusive mode)", userID, group.ID)
+
+		if err := groups.RemoveUser(identityClient, group.ID, userID).ExtractErr(); err != nil {
+			return fmt.Errorf("Error removing ECL user %s from group %s: %s", userID, group.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// resourceIdentityUserGroupMembershipV3ExpandGroupIDs converts a group_ids
+// set into a plain slice of group IDs.
+func resourceIdentityUserGroupMembershipV3ExpandGroupIDs(set *schema.Set) []string {
+	groupIDs := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		groupIDs = append(groupIDs, v.(string))
+	}
+
+	return groupIDs
+}
@@ -3,6 +3,7 @@ package ecl
 import (
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/nttcom/eclcloud/ecl/identity/v3/users"
@@ -13,6 +14,7 @@ var userOptions = map[users.Option]string{
 	users.IgnorePasswordExpiry:             "ignore_password_expiry",
 	users.IgnoreLockoutFailureAttempts:     "ignore_lockout_failure_attempts",
 	users.MultiFactorAuthEnabled:           "multi_factor_auth_enabled",
+	users.MustChangePasswordOnNextLogin:    "must_change_password_on_next_login",
 }
 
 func resourceIdentityUserV3() *schema.Resource {
@@ -22,7 +24,7 @@ func resourceIdentityUserV3() *schema.Resource {
 		Update: resourceIdentityUserV3Update,
 		Delete: resourceIdentityUserV3Delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceIdentityUserV3Import,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -65,6 +67,81 @@ func resourceIdentityUserV3() *schema.Resource {
 				Sensitive: true,
 			},
 
+			// password_wo's own diff is only ever surfaced when
+			// password_version changes, so editing it alone doesn't
+			// churn the plan; bump password_version to force a
+			// rotation to the new value.
+			"password_wo": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return !d.HasChange("password_version")
+				},
+			},
+
+			"password_version": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+
+			"generated_password": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"password_expires_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"generate_password": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"length": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  16,
+						},
+
+						"special": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
+						"upper": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
+						"lower": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
+						"numeric": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
+						"min_special": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+					},
+				},
+			},
+
 			"region": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -94,6 +171,11 @@ func resourceIdentityUserV3() *schema.Resource {
 				Optional: true,
 			},
 
+			"must_change_password_on_next_login": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
 			"multi_factor_auth_rule": &schema.Schema{
 				Type:     schema.TypeList,
 				Optional: true,
@@ -112,6 +194,42 @@ func resourceIdentityUserV3() *schema.Resource {
 	}
 }
 
+// resourceIdentityUserV3ResolvePassword returns the password to send to the
+// API and, when it was generated by the provider, the value to store in
+// generated_password. Precedence is password, then password_wo, then a
+// password generated locally from the generate_password block.
+func resourceIdentityUserV3ResolvePassword(d *schema.ResourceData) (password string, generated string, err error) {
+	if v := d.Get("password").(string); v != "" {
+		return v, "", nil
+	}
+
+	if v := d.Get("password_wo").(string); v != "" {
+		return v, "", nil
+	}
+
+	genList := d.Get("generate_password").([]interface{})
+	if len(genList) == 0 || genList[0] == nil {
+		return "", "", nil
+	}
+
+	genMap := genList[0].(map[string]interface{})
+	opts := generatePasswordOpts{
+		Length:     genMap["length"].(int),
+		Special:    genMap["special"].(bool),
+		Upper:      genMap["upper"].(bool),
+		Lower:      genMap["lower"].(bool),
+		Numeric:    genMap["numeric"].(bool),
+		MinSpecial: genMap["min_special"].(int),
+	}
+
+	generated, err = generatePassword(opts)
+	if err != nil {
+		return "", "", fmt.Errorf("Error generating ECL user password: %s", err)
+	}
+
+	return generated, generated, nil
+}
+
 func resourceIdentityUserV3Create(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	identityClient, err := config.identityV3Client(GetRegion(d, config))
@@ -148,7 +266,11 @@ func resourceIdentityUserV3Create(d *schema.ResourceData, meta interface{}) erro
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
 
 	// Add password here so it wouldn't go in the above log entry
-	createOpts.Password = d.Get("password").(string)
+	password, generated, err := resourceIdentityUserV3ResolvePassword(d)
+	if err != nil {
+		return err
+	}
+	createOpts.Password = password
 
 	user, err := users.Create(identityClient, createOpts).Extract()
 	if err != nil {
@@ -156,6 +278,7 @@ func resourceIdentityUserV3Create(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	d.SetId(user.ID)
+	d.Set("generated_password", generated)
 
 	return resourceIdentityUserV3Read(d, meta)
 }
@@ -189,19 +312,102 @@ func resourceIdentityUserV3Read(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
-	mfaRules := []map[string]interface{}{}
-	if v, ok := options["multi_factor_auth_rules"].([]interface{}); ok {
-		for _, v := range v {
-			mfaRule := map[string]interface{}{
-				"rule": v,
+	if v, ok := options["password_expires_at"].(string); ok {
+		d.Set("password_expires_at", v)
+	}
+
+	if raw, ok := options["multi_factor_auth_rules"]; ok {
+		d.Set("multi_factor_auth_rule", resourceIdentityUserV3FlattenMFARules(raw))
+	}
+
+	return nil
+}
+
+// resourceIdentityUserV3NormalizeMFARules normalizes the server's
+// multi_factor_auth_rules representation into a slice of rules, where each
+// rule is itself a slice of factor names. The server may send each rule as
+// either []interface{} or []string.
+func resourceIdentityUserV3NormalizeMFARules(raw interface{}) [][]interface{} {
+	var rules [][]interface{}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return rules
+	}
+
+	for _, item := range list {
+		switch rule := item.(type) {
+		case []interface{}:
+			rules = append(rules, rule)
+		case []string:
+			factors := make([]interface{}, len(rule))
+			for i, factor := range rule {
+				factors[i] = factor
 			}
-			mfaRules = append(mfaRules, mfaRule)
+			rules = append(rules, factors)
 		}
+	}
+
+	return rules
+}
+
+// resourceIdentityUserV3FlattenMFARules converts the server's
+// multi_factor_auth_rules representation into the schema shape expected by
+// the multi_factor_auth_rule attribute.
+func resourceIdentityUserV3FlattenMFARules(raw interface{}) []map[string]interface{} {
+	mfaRules := []map[string]interface{}{}
 
-		d.Set("multi_factor_auth_rule", mfaRules)
+	for _, rule := range resourceIdentityUserV3NormalizeMFARules(raw) {
+		mfaRules = append(mfaRules, map[string]interface{}{
+			"rule": rule,
+		})
 	}
 
-	return nil
+	return mfaRules
+}
+
+// resourceIdentityUserV3Import fetches the user and explicitly populates
+// every userOptions key (defaulting missing ones to false) and the MFA
+// rules, so the first plan after `terraform import` shows no diff.
+func resourceIdentityUserV3Import(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+
+	region := os.Getenv("ECL_REGION")
+	if region == "" {
+		region = GetRegion(d, config)
+	}
+	d.Set("region", region)
+
+	identityClient, err := config.identityV3Client(region)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	user, err := users.Get(identityClient, d.Id()).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving ECL user: %s", err)
+	}
+
+	options := user.Options
+	for _, option := range userOptions {
+		v, ok := options[option]
+		if !ok {
+			d.Set(option, false)
+			continue
+		}
+
+		b, ok := v.(bool)
+		if !ok {
+			d.Set(option, false)
+			continue
+		}
+
+		d.Set(option, b)
+	}
+
+	d.Set("multi_factor_auth_rule", resourceIdentityUserV3FlattenMFARules(options["multi_factor_auth_rules"]))
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceIdentityUserV3Update(d *schema.ResourceData, meta interface{}) error {
@@ -254,12 +460,16 @@ func resourceIdentityUserV3Update(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
-	// Build the MFA rules
+	// Build the MFA rules. Send an explicit empty list rather than
+	// omitting the key when multi_factor_auth_rule is cleared, so the
+	// server's existing rules actually get removed instead of left stale.
 	if d.HasChange("multi_factor_auth_rule") {
+		hasChange = true
 		mfaRules := resourceIdentityUserV3BuildMFARules(d.Get("multi_factor_auth_rule").([]interface{}))
-		if len(mfaRules) > 0 {
-			options[users.MultiFactorAuthRules] = mfaRules
+		if mfaRules == nil {
+			mfaRules = []interface{}{}
 		}
+		options[users.MultiFactorAuthRules] = mfaRules
 	}
 
 	updateOpts.Options = options
@@ -268,9 +478,15 @@ func resourceIdentityUserV3Update(d *schema.ResourceData, meta interface{}) erro
 		log.Printf("[DEBUG] Update Options: %#v", updateOpts)
 	}
 
-	if d.HasChange("password") {
+	if d.HasChange("password") || d.HasChange("password_wo") || d.HasChange("password_version") {
 		hasChange = true
-		updateOpts.Password = d.Get("password").(string)
+
+		password, generated, err := resourceIdentityUserV3ResolvePassword(d)
+		if err != nil {
+			return err
+		}
+		updateOpts.Password = password
+		d.Set("generated_password", generated)
 	}
 
 	if hasChange {
@@ -0,0 +1,127 @@
+package ecl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/nttcom/eclcloud/ecl/identity/v3/users"
+)
+
+func TestAccIdentityV3User_importOptionsAndMFARules(t *testing.T) {
+	var user users.User
+	userName := acctest.RandomWithPrefix("tf-acc-user")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIdentityV3UserImportMFADestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdentityV3UserImportOptionsAndMFARules(userName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIdentityV3UserImportMFAExists("ecl_identity_user_v3.user_1", &user),
+					resource.TestCheckResourceAttr(
+						"ecl_identity_user_v3.user_1", "ignore_change_password_upon_first_use", "true"),
+					resource.TestCheckResourceAttr(
+						"ecl_identity_user_v3.user_1", "ignore_password_expiry", "true"),
+					resource.TestCheckResourceAttr(
+						"ecl_identity_user_v3.user_1", "ignore_lockout_failure_attempts", "true"),
+					resource.TestCheckResourceAttr(
+						"ecl_identity_user_v3.user_1", "multi_factor_auth_enabled", "true"),
+					resource.TestCheckResourceAttr(
+						"ecl_identity_user_v3.user_1", "must_change_password_on_next_login", "true"),
+					resource.TestCheckResourceAttr(
+						"ecl_identity_user_v3.user_1", "multi_factor_auth_rule.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "ecl_identity_user_v3.user_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// password is never returned by the API, so it cannot be
+				// reconstructed by the importer.
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func testAccCheckIdentityV3UserImportMFAExists(n string, user *users.User) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		identityClient, err := config.identityV3Client(testAccRegion)
+		if err != nil {
+			return fmt.Errorf("Error creating ECL identity client: %s", err)
+		}
+
+		found, err := users.Get(identityClient, rs.Primary.ID).Extract()
+		if err != nil {
+			return err
+		}
+
+		if found.ID != rs.Primary.ID {
+			return fmt.Errorf("User not found")
+		}
+
+		*user = *found
+
+		return nil
+	}
+}
+
+func testAccCheckIdentityV3UserImportMFADestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	identityClient, err := config.identityV3Client(testAccRegion)
+	if err != nil {
+		return fmt.Errorf("Error creating ECL identity client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "ecl_identity_user_v3" {
+			continue
+		}
+
+		_, err := users.Get(identityClient, rs.Primary.ID).Extract()
+		if err == nil {
+			return fmt.Errorf("User still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccIdentityV3UserImportOptionsAndMFARules(userName string) string {
+	return fmt.Sprintf(`
+resource "ecl_identity_user_v3" "user_1" {
+  name     = "%s"
+  password = "pwd1R00t"
+  enabled  = true
+
+  ignore_change_password_upon_first_use = true
+  ignore_password_expiry                = true
+  ignore_lockout_failure_attempts       = true
+  multi_factor_auth_enabled             = true
+  must_change_password_on_next_login    = true
+
+  multi_factor_auth_rule {
+    rule = ["password", "totp"]
+  }
+
+  multi_factor_auth_rule {
+    rule = ["password", "hardware_token"]
+  }
+}
+`, userName)
+}
@@ -0,0 +1,103 @@
+package ecl
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+const (
+	passwordLowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	passwordUpperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordNumberChars  = "0123456789"
+	passwordSpecialChars = "!@#$%&*()-_=+[]{}<>:?"
+)
+
+// generatePasswordOpts mirrors the generate_password block on
+// resourceIdentityUserV3.
+type generatePasswordOpts struct {
+	Length     int
+	Special    bool
+	Upper      bool
+	Lower      bool
+	Numeric    bool
+	MinSpecial int
+}
+
+// generatePassword builds a random password satisfying opts, using
+// crypto/rand so the result is suitable for a real credential.
+func generatePassword(opts generatePasswordOpts) (string, error) {
+	if opts.Length < 1 {
+		return "", fmt.Errorf("generate_password.length must be greater than zero")
+	}
+
+	var charset string
+	if opts.Lower {
+		charset += passwordLowerChars
+	}
+	if opts.Upper {
+		charset += passwordUpperChars
+	}
+	if opts.Numeric {
+		charset += passwordNumberChars
+	}
+	if opts.Special {
+		charset += passwordSpecialChars
+	}
+	if charset == "" {
+		return "", fmt.Errorf("generate_password must allow at least one character class")
+	}
+
+	minSpecial := 0
+	if opts.Special {
+		minSpecial = opts.MinSpecial
+		if minSpecial > opts.Length {
+			return "", fmt.Errorf("generate_password.min_special cannot be greater than length")
+		}
+	}
+
+	result := make([]byte, opts.Length)
+
+	for i := 0; i < minSpecial; i++ {
+		c, err := randomChar(passwordSpecialChars)
+		if err != nil {
+			return "", err
+		}
+		result[i] = c
+	}
+
+	for i := minSpecial; i < opts.Length; i++ {
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		result[i] = c
+	}
+
+	// Shuffle so the required special characters aren't always at the front.
+	for i := len(result) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	i, err := randomIndex(len(charset))
+	if err != nil {
+		return 0, err
+	}
+	return charset[i], nil
+}
+
+func randomIndex(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, fmt.Errorf("Error generating random password: %s", err)
+	}
+	return int(n.Int64()), nil
+}